@@ -0,0 +1,71 @@
+// Package auth provides API key authentication for the upload service.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// Principal identifies the authenticated caller a request was made on
+// behalf of.
+type Principal struct {
+	UserID  string
+	IsAdmin bool
+}
+
+type contextKey int
+
+const principalKey contextKey = 0
+
+// Middleware authenticates requests against the api_keys table, using
+// either the Authorization: Bearer header or an "auth" cookie, and rejects
+// unauthenticated or revoked keys with 401.
+func Middleware(db *sql.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := extractKey(r)
+			if key == "" {
+				http.Error(w, "Missing credentials", http.StatusUnauthorized)
+				return
+			}
+
+			sum := sha256.Sum256([]byte(key))
+			hash := hex.EncodeToString(sum[:])
+
+			var p Principal
+			var revoked sql.NullTime
+			err := db.QueryRow(
+				"SELECT user_id, is_admin, revoked_at FROM api_keys WHERE key_hash = $1",
+				hash,
+			).Scan(&p.UserID, &p.IsAdmin, &revoked)
+			if err != nil || revoked.Valid {
+				http.Error(w, "Invalid API key", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey, p)))
+		})
+	}
+}
+
+// extractKey pulls the API key from the Authorization header, falling back
+// to the "auth" cookie.
+func extractKey(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		return strings.TrimPrefix(h, "Bearer ")
+	}
+	if c, err := r.Cookie("auth"); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// FromContext returns the Principal authenticated by Middleware, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey).(Principal)
+	return p, ok
+}
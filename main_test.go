@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLimitedReaderAllowsExactlyAtLimit(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 10)
+	lr := &limitedReader{r: bytes.NewReader(data), limit: 10}
+
+	got, err := io.ReadAll(lr)
+	if err != nil {
+		t.Fatalf("unexpected error for a part exactly at the limit: %v", err)
+	}
+	if len(got) != 10 {
+		t.Fatalf("got %d bytes, want 10", len(got))
+	}
+}
+
+func TestLimitedReaderRejectsOneByteOver(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 11)
+	lr := &limitedReader{r: bytes.NewReader(data), limit: 10}
+
+	_, err := io.ReadAll(lr)
+	if err != errLimitExceeded {
+		t.Fatalf("got err %v, want errLimitExceeded", err)
+	}
+}
+
+func TestLimitedReaderViaIOCopy(t *testing.T) {
+	// io.Copy keeps reading until it sees EOF, so it always issues one more
+	// Read after the last byte; a reader sized exactly to the limit must not
+	// trip on it.
+	data := bytes.Repeat([]byte("a"), 10)
+	lr := &limitedReader{r: bytes.NewReader(data), limit: 10}
+
+	var dst bytes.Buffer
+	n, err := io.Copy(&dst, lr)
+	if err != nil {
+		t.Fatalf("io.Copy returned error for a part exactly at the limit: %v", err)
+	}
+	if n != 10 {
+		t.Fatalf("copied %d bytes, want 10", n)
+	}
+}
+
+func TestAsciiFallbackFilename(t *testing.T) {
+	cases := map[string]string{
+		"report.pdf":     "report.pdf",
+		`weird"name.txt`: "weird_name.txt",
+		"café.png":       "caf_.png",
+	}
+	for in, want := range cases {
+		if got := asciiFallbackFilename(in); got != want {
+			t.Errorf("asciiFallbackFilename(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	got := contentDisposition("attachment", "café.png")
+	if !strings.HasPrefix(got, "attachment; filename=") {
+		t.Fatalf("missing disposition/filename prefix: %q", got)
+	}
+	if !strings.Contains(got, `filename="caf_.png"`) {
+		t.Errorf("missing ASCII fallback filename param: %q", got)
+	}
+	if !strings.Contains(got, "filename*=UTF-8''caf%C3%A9.png") {
+		t.Errorf("missing RFC 5987 encoded filename param: %q", got)
+	}
+}
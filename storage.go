@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Info describes a stored blob, independent of which backend holds it
+type Info struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage abstracts the underlying blob store so handlers don't need to
+// know whether content lives on local disk or in an object store
+type Storage interface {
+	Put(id string, r io.Reader) (size int64, err error)
+	Get(id string) (io.ReadCloser, error)
+	Delete(id string) error
+	Stat(id string) (Info, error)
+}
+
+// newStorage constructs the configured Storage backend from the
+// STORAGE_BACKEND environment variable, defaulting to local disk
+func newStorage() (Storage, error) {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "local":
+		return newLocalStorage(uploadDir), nil
+	case "s3", "gcs":
+		return newS3Storage()
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
+
+// localStorage implements Storage on top of the local filesystem, keyed by
+// blob ID (the content hash) under uploadDir
+type localStorage struct {
+	dir string
+}
+
+func newLocalStorage(dir string) *localStorage {
+	return &localStorage{dir: dir}
+}
+
+func (l *localStorage) path(id string) string {
+	return filepath.Join(l.dir, id)
+}
+
+func (l *localStorage) Put(id string, r io.Reader) (int64, error) {
+	f, err := os.Create(l.path(id))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(f, r)
+}
+
+func (l *localStorage) Get(id string) (io.ReadCloser, error) {
+	return os.Open(l.path(id))
+}
+
+func (l *localStorage) Delete(id string) error {
+	return os.Remove(l.path(id))
+}
+
+func (l *localStorage) Stat(id string) (Info, error) {
+	fi, err := os.Stat(l.path(id))
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3Storage implements Storage against an S3-compatible object store,
+// configured via S3_BUCKET / S3_ENDPOINT and the usual AWS credential chain.
+// GCS is served by the same driver through its S3-compatible XML API when
+// STORAGE_BACKEND=gcs and S3_ENDPOINT points at storage.googleapis.com.
+type s3Storage struct {
+	client *s3.Client
+	bucket string
+}
+
+func newS3Storage() (*s3Storage, error) {
+	bucket := os.Getenv("S3_BUCKET")
+	if bucket == "" {
+		return nil, fmt.Errorf("S3_BUCKET must be set when STORAGE_BACKEND=s3 or gcs")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &s3Storage{client: client, bucket: bucket}, nil
+}
+
+func (s *s3Storage) Put(id string, r io.Reader) (int64, error) {
+	counting := &countingReader{r: r}
+	_, err := manager.NewUploader(s.client).Upload(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(id),
+		Body:   counting,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return counting.n, nil
+}
+
+func (s *s3Storage) Get(id string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *s3Storage) Delete(id string) error {
+	_, err := s.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(id),
+	})
+	return err
+}
+
+func (s *s3Storage) Stat(id string) (Info, error) {
+	out, err := s.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(id),
+	})
+	if err != nil {
+		return Info{}, err
+	}
+	info := Info{Size: aws.ToInt64(out.ContentLength)}
+	if out.LastModified != nil {
+		info.ModTime = *out.LastModified
+	}
+	return info, nil
+}
+
+// PresignedGetURL generates a time-limited URL clients can download from
+// directly, bypassing the service's own download proxy
+func (s *s3Storage) PresignedGetURL(id string, expires time.Duration) (string, error) {
+	req, err := s3.NewPresignClient(s.client).PresignGetObject(context.Background(),
+		&s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(id)},
+		s3.WithPresignExpires(expires),
+	)
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+// countingReader wraps an io.Reader to track the number of bytes read,
+// since the S3 upload manager doesn't report the final object size
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}